@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package azure
+
+// Well-known cloud configuration names.
+const (
+	// AzurePublicCloudName is the name of the Azure public cloud.
+	AzurePublicCloudName = "AzurePublic"
+	// AzureGovCloudName is the name of the Azure Government cloud.
+	AzureGovCloudName = "AzureGovernment"
+	// AzureChinaCloudName is the name of the Azure China cloud.
+	AzureChinaCloudName = "AzureChina"
+	// AzureStackCloudName is the name used for Azure Stack Hub and other custom/sovereign clouds whose endpoints are
+	// not well-known and must be supplied explicitly via CloudConfiguration.
+	AzureStackCloudName = "AzureStack"
+)
+
+// AzureGovRegionPrefixes are the region name prefixes used to detect the Azure Government cloud from a region name.
+var AzureGovRegionPrefixes = []string{"usgov", "usdod"}
+
+// AzureChinaRegionPrefixes are the region name prefixes used to detect the Azure China cloud from a region name.
+var AzureChinaRegionPrefixes = []string{"china"}
+
+// CloudConfiguration describes the Azure cloud instance the extension talks to. For the well-known clouds (Azure
+// Public, Government, China) only Name needs to be set. For Azure Stack Hub and other sovereign/air-gapped clouds
+// that don't have a well-known endpoint, Name should be set to AzureStackCloudName and the remaining fields used to
+// describe the cloud's endpoints explicitly.
+type CloudConfiguration struct {
+	// Name is the name of the cloud configuration to use. One of AzurePublicCloudName, AzureGovCloudName,
+	// AzureChinaCloudName, or AzureStackCloudName for a custom cloud described by the fields below.
+	Name string `json:"name"`
+	// ActiveDirectoryAuthorityHost is the Azure Active Directory login endpoint to use for this cloud. Only
+	// meaningful (and required) when Name is AzureStackCloudName.
+	// +optional
+	ActiveDirectoryAuthorityHost *string `json:"activeDirectoryAuthorityHost,omitempty"`
+	// ResourceManagerEndpoint is the Azure Resource Manager endpoint to use for this cloud. Only meaningful (and
+	// required) when Name is AzureStackCloudName.
+	// +optional
+	ResourceManagerEndpoint *string `json:"resourceManagerEndpoint,omitempty"`
+	// ServiceConfiguration allows overriding the audience and endpoint used for individual Azure services (e.g.
+	// "resourceManager", "storage") for this cloud. Only meaningful when Name is AzureStackCloudName.
+	// +optional
+	ServiceConfiguration []ServiceConfiguration `json:"serviceConfiguration,omitempty"`
+}
+
+// ServiceConfiguration overrides the audience and endpoint of a single Azure service for a custom CloudConfiguration.
+type ServiceConfiguration struct {
+	// Name is the name of the Azure service this override applies to, e.g. "resourceManager" or "storage".
+	Name string `json:"name"`
+	// Audience is the audience to request tokens for when talking to this service.
+	Audience string `json:"audience"`
+	// Endpoint is the base endpoint of this service.
+	Endpoint string `json:"endpoint"`
+}
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package azure
+
+// BackupBucketConfig represents the configuration for a backup bucket.
+type BackupBucketConfig struct {
+	// Immutability defines the immutability configuration for the backup bucket's storage account.
+	// +optional
+	Immutability *ImmutableConfig `json:"immutability,omitempty"`
+	// Encryption defines the customer-managed encryption configuration for the backup bucket's storage account.
+	// +optional
+	Encryption *EncryptionConfig `json:"encryption,omitempty"`
+}
+
+// EncryptionConfig represents the customer-managed key (CMK) configuration for a backup bucket's storage account.
+type EncryptionConfig struct {
+	// KeyVaultKeyURI is the URI (including version) of the Key Vault key to use for encrypting the storage account,
+	// e.g. "https://my-vault.vault.azure.net/keys/my-key/abcdef0123456789abcdef0123456789".
+	KeyVaultKeyURI string `json:"keyVaultKeyURI"`
+	// UserAssignedIdentityID is the resource ID of the user-assigned managed identity the storage account should
+	// use to access the Key Vault key. If empty, the storage account's system-assigned identity is used.
+	// +optional
+	UserAssignedIdentityID *string `json:"userAssignedIdentityID,omitempty"`
+	// RequireInfrastructureEncryption enables a second, platform-managed layer of encryption underneath the
+	// customer-managed key, for defense in depth.
+	// +optional
+	RequireInfrastructureEncryption bool `json:"requireInfrastructureEncryption,omitempty"`
+}
+
+// ImmutableConfig represents the WORM (write-once-read-many) configuration for a backup bucket's storage account.
+type ImmutableConfig struct {
+	// RetentionType specifies the type of retention for the backup bucket.
+	// Currently only "bucket" is supported, which applies the retention policy to the storage account's
+	// default backup container as a whole.
+	RetentionType string `json:"retentionType"`
+	// RetentionDays specifies the immutability retention period for the backup bucket, in days.
+	RetentionDays int32 `json:"retentionDays"`
+	// Locked specifies whether the immutability policy is locked (irreversible) or can still be shortened/removed.
+	// +optional
+	Locked bool `json:"locked,omitempty"`
+}
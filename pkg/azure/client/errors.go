@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/go-autorest/autorest"
+)
+
+// IsAzureAPIThrottled tries to determine if an API error is due to the caller being throttled (HTTP 429).
+func IsAzureAPIThrottled(err error) bool {
+	return isAzureAPIStatusError(err, http.StatusTooManyRequests)
+}
+
+// RetryAfter returns the duration the caller should wait before retrying, as parsed from the error's "Retry-After"
+// response header, and whether such a header was present at all. Callers should fall back to their own backoff if
+// ok is false - not every throttling response carries this header.
+func RetryAfter(err error) (d time.Duration, ok bool) {
+	var responseErr *azcore.ResponseError
+	if !errors.As(err, &responseErr) || responseErr.RawResponse == nil {
+		return 0, false
+	}
+
+	retryAfter := responseErr.RawResponse.Header.Get("Retry-After")
+	if retryAfter == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(retryAfter); err == nil {
+		return time.Until(at), true
+	}
+	return 0, false
+}
+
+// IsAzureAPIConflict tries to determine if an API error is due to the targeted resource already existing or being
+// in a conflicting state (ARM error code "Conflict"), as opposed to another operation already being in progress on
+// it (see IsAzureAPIAnotherOperationInProgress), which callers typically want to retry instead of failing on.
+func IsAzureAPIConflict(err error) bool {
+	if !isAzureAPIStatusError(err, http.StatusConflict) {
+		return false
+	}
+	return armErrorCodeIs(err, "Conflict")
+}
+
+// IsAzureAPIAnotherOperationInProgress tries to determine if an API error is due to another operation already being
+// in progress on the targeted resource (ARM error code "AnotherOperationInProgress"). Unlike a generic conflict,
+// this is expected to resolve itself once the other operation finishes, so it is safe to retry.
+func IsAzureAPIAnotherOperationInProgress(err error) bool {
+	if !isAzureAPIStatusError(err, http.StatusConflict) {
+		return false
+	}
+	return armErrorCodeIs(err, "AnotherOperationInProgress")
+}
+
+// IsAzureAPIQuotaExceeded tries to determine if an API error is due to a subscription or resource quota being
+// exceeded (ARM error codes "SubscriptionQuotaExceeded" / "QuotaExceeded"). Retrying this without operator
+// intervention (e.g. requesting a quota increase) will not help.
+func IsAzureAPIQuotaExceeded(err error) bool {
+	return armErrorCodeIs(err, "SubscriptionQuotaExceeded") || armErrorCodeIs(err, "QuotaExceeded")
+}
+
+// IsAzureAPITransient tries to determine if an API error is a transient failure - a 5xx response or a network-level
+// error below the HTTP layer - that is generally safe to retry without any special handling.
+func IsAzureAPITransient(err error) bool {
+	var responseErr *azcore.ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var detailedErr autorest.DetailedError
+	if errors.As(err, &detailedErr) {
+		if code, ok := detailedErr.StatusCode.(int); ok {
+			return code >= http.StatusInternalServerError
+		}
+	}
+
+	// Neither error type could be extracted, which for errors coming from the Azure SDK means the request never
+	// got a response at all (DNS failure, connection reset, timeout, ...) - treat that as transient too.
+	return isNetworkError(err)
+}
+
+// armErrorCodeIs reports whether err carries the given ARM inner error code, regardless of which SDK generation
+// (old autorest-based clients vs. the new azcore-based ones) produced it.
+func armErrorCodeIs(err error, code string) bool {
+	var responseErr *azcore.ResponseError
+	if errors.As(err, &responseErr) && strings.EqualFold(responseErr.ErrorCode, code) {
+		return true
+	}
+
+	// autorest.DetailedError does not parse the inner ARM error code into a dedicated field, so fall back to
+	// matching it in the serialized error message.
+	var detailedErr autorest.DetailedError
+	if errors.As(err, &detailedErr) {
+		return strings.Contains(detailedErr.Error(), code)
+	}
+
+	return false
+}
+
+// isNetworkError reports whether err is a network-layer failure - a timeout, connection reset, or DNS lookup
+// failure - that happened before any HTTP response was received, as opposed to a plain application error that
+// simply isn't an *azcore.ResponseError or autorest.DetailedError. Treating every unrecognized error as a network
+// error used to mean local errors (e.g. "storage account has no usable keys") were retried by withRetry even though
+// retrying them can never succeed, so this only matches genuine transport-level failures.
+func isNetworkError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// The SDK's HTTP transport reports a timed-out request as context.DeadlineExceeded rather than wrapping it in
+	// a net.Error.
+	return errors.Is(err, context.DeadlineExceeded)
+}
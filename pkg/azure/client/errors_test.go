@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+)
+
+type fakeNetError struct{ error }
+
+func (fakeNetError) Timeout() bool   { return true }
+func (fakeNetError) Temporary() bool { return true }
+
+var _ net.Error = fakeNetError{}
+
+func TestIsNetworkError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil error", nil, false},
+		{"plain local error", fmt.Errorf("storage account %q has no usable keys", "foo"), false},
+		{"wrapped plain local error", fmt.Errorf("wrapped: %w", errors.New("boom")), false},
+		{"net.Error", fakeNetError{errors.New("dial tcp: timeout")}, true},
+		{"wrapped net.Error", fmt.Errorf("wrapped: %w", fakeNetError{errors.New("dial tcp: timeout")}), true},
+		{"context deadline exceeded", context.DeadlineExceeded, true},
+		{"wrapped context deadline exceeded", fmt.Errorf("wrapped: %w", context.DeadlineExceeded), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNetworkError(tt.err); got != tt.want {
+				t.Errorf("isNetworkError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+
+	"github.com/gardener/gardener-extension-provider-azure/pkg/apis/azure"
+)
+
+// Factory is an interface for creating clients for the various Azure services.
+type Factory interface {
+	// Group returns a client for the Azure resource group service.
+	Group() (ResourceGroup, error)
+	// StorageAccount returns a client for the Azure storage account service.
+	StorageAccount() (StorageAccount, error)
+}
+
+// CredentialsConfig bundles the ways the factory can authenticate against Azure AD. At most one of the
+// identity-based options should be set; if none are set, the factory uses the system-assigned managed identity.
+type CredentialsConfig struct {
+	// EnableWorkloadIdentity makes the factory authenticate using the federated token mounted for the pod
+	// (azidentity.NewWorkloadIdentityCredential) instead of a storage-account key or client secret.
+	EnableWorkloadIdentity bool
+	// ManagedIdentityClientID, if set, makes the factory authenticate as the given user-assigned managed identity
+	// (azidentity.NewManagedIdentityCredential). If unset and no other option is set, the system-assigned identity
+	// is used.
+	ManagedIdentityClientID *string
+	// UseDefaultCredentialChain makes the factory fall back to azidentity.NewDefaultAzureCredential, which probes
+	// environment variables, managed identity, the Azure CLI and other common credential sources in order. This is
+	// mainly useful for local development against a real subscription.
+	UseDefaultCredentialChain bool
+}
+
+// WorkloadIdentity carries the Azure AD identity coordinates the etcd-backup-restore sidecar needs to authenticate
+// to Blob storage itself, once the factory is configured to use Workload Identity or a managed identity instead of
+// storage-account keys.
+type WorkloadIdentity struct {
+	// TenantID is the Azure AD tenant the identity belongs to.
+	TenantID string
+	// ClientID is the Azure AD application (client) ID of the identity (or the user-assigned managed identity).
+	ClientID string
+}
+
+// factory is the production Factory implementation. It builds its credential once, at construction time, and builds
+// a new service client on every call to Group() or StorageAccount(), so that a caller who only ever needs e.g.
+// StorageAccount() never pays for setting up a ResourceGroup client.
+type factory struct {
+	subscriptionID string
+	credential     azcore.TokenCredential
+	clientOptions  arm.ClientOptions
+}
+
+// NewFactory creates a Factory that authenticates against Azure AD as described by credentials, and talks to the
+// given subscription in the Azure cloud described by cloudConfiguration (or, if cloudConfiguration is nil, the
+// well-known cloud inferred from region).
+func NewFactory(subscriptionID string, credentials CredentialsConfig, cloudConfiguration *azure.CloudConfiguration, region *string) (Factory, error) {
+	credential, err := azureTokenCredential(credentials)
+	if err != nil {
+		if IsAzureAPICredentialUnavailable(err) {
+			return nil, fmt.Errorf("the configured Azure credential source is not available in this environment: %w", err)
+		}
+		return nil, fmt.Errorf("failed to build Azure AD credential: %w", err)
+	}
+
+	cloud, err := AzureCloudConfiguration(cloudConfiguration, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return &factory{
+		subscriptionID: subscriptionID,
+		credential:     credential,
+		clientOptions:  arm.ClientOptions{ClientOptions: azcore.ClientOptions{Cloud: cloud}},
+	}, nil
+}
+
+// Group returns a client for the Azure resource group service.
+func (f *factory) Group() (ResourceGroup, error) {
+	groupsClient, err := armresources.NewResourceGroupsClient(f.subscriptionID, f.credential, &f.clientOptions)
+	if err != nil {
+		return nil, err
+	}
+	return NewResourceGroupClient(groupsClient), nil
+}
+
+// StorageAccount returns a client for the Azure storage account service.
+func (f *factory) StorageAccount() (StorageAccount, error) {
+	accountsClient, err := armstorage.NewAccountsClient(f.subscriptionID, f.credential, &f.clientOptions)
+	if err != nil {
+		return nil, err
+	}
+	blobServicesClient, err := armstorage.NewBlobServicesClient(f.subscriptionID, f.credential, &f.clientOptions)
+	if err != nil {
+		return nil, err
+	}
+	containersClient, err := armstorage.NewBlobContainersClient(f.subscriptionID, f.credential, &f.clientOptions)
+	if err != nil {
+		return nil, err
+	}
+	return NewStorageAccountClient(accountsClient, blobServicesClient, containersClient), nil
+}
+
+// azureTokenCredential builds the azcore.TokenCredential described by the given CredentialsConfig.
+func azureTokenCredential(config CredentialsConfig) (azcore.TokenCredential, error) {
+	switch {
+	case config.EnableWorkloadIdentity:
+		return azidentity.NewWorkloadIdentityCredential(nil)
+	case config.ManagedIdentityClientID != nil:
+		return azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
+			ID: azidentity.ClientID(*config.ManagedIdentityClientID),
+		})
+	case config.UseDefaultCredentialChain:
+		return azidentity.NewDefaultAzureCredential(nil)
+	default:
+		return azidentity.NewManagedIdentityCredential(nil)
+	}
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+func TestAzureTokenCredential(t *testing.T) {
+	t.Run("managed identity client ID selects a user-assigned managed identity", func(t *testing.T) {
+		clientID := "11111111-1111-1111-1111-111111111111"
+		cred, err := azureTokenCredential(CredentialsConfig{ManagedIdentityClientID: &clientID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := cred.(*azidentity.ManagedIdentityCredential); !ok {
+			t.Errorf("got %T, want *azidentity.ManagedIdentityCredential", cred)
+		}
+	})
+
+	t.Run("no options set falls back to the system-assigned managed identity", func(t *testing.T) {
+		cred, err := azureTokenCredential(CredentialsConfig{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := cred.(*azidentity.ManagedIdentityCredential); !ok {
+			t.Errorf("got %T, want *azidentity.ManagedIdentityCredential", cred)
+		}
+	})
+}
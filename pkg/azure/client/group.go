@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// ResourceGroup is an interface for the Azure resource group service.
+type ResourceGroup interface {
+	// CreateOrUpdate creates or updates the resource group with the given name.
+	CreateOrUpdate(ctx context.Context, resourceGroupName string, group armresources.ResourceGroup) (*armresources.ResourceGroup, error)
+}
+
+type resourceGroup struct {
+	client *armresources.ResourceGroupsClient
+}
+
+// NewResourceGroupClient creates a new ResourceGroup client.
+func NewResourceGroupClient(client *armresources.ResourceGroupsClient) ResourceGroup {
+	return &resourceGroup{client: client}
+}
+
+// CreateOrUpdate creates or updates the resource group with the given name.
+func (r *resourceGroup) CreateOrUpdate(ctx context.Context, resourceGroupName string, group armresources.ResourceGroup) (*armresources.ResourceGroup, error) {
+	res, err := r.client.CreateOrUpdate(ctx, resourceGroupName, group, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &res.ResourceGroup, nil
+}
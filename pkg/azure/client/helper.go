@@ -65,7 +65,39 @@ func IsAzureAPIUnauthorized(err error) bool {
 	}
 
 	inErr := &azidentity.AuthenticationFailedError{}
-	return errors.As(err, &inErr)
+	if errors.As(err, &inErr) {
+		return true
+	}
+
+	return false
+}
+
+// IsAzureAPIKeyVaultAccessDenied tries to determine whether an API error is due to the storage account (or the
+// identity acting on its behalf) being denied access to the Key Vault key used for customer-managed encryption, as
+// opposed to a generic authorization failure against the Azure Resource Manager API. ARM reports this as a 403 with
+// an inner error code of "KeyVaultAccessDenied" or "KeyVaultKeyAccessDenied", rather than as a 401, so it is not
+// caught by IsAzureAPIUnauthorized.
+func IsAzureAPIKeyVaultAccessDenied(err error) bool {
+	if !isAzureAPIStatusError(err, http.StatusForbidden) {
+		return false
+	}
+	return armErrorCodeIs(err, "KeyVaultAccessDenied") || armErrorCodeIs(err, "KeyVaultKeyAccessDenied")
+}
+
+// IsAzureAPICredentialUnavailable tries to determine whether an error is due to the configured credential source
+// (Workload Identity, Managed Identity, ...) not being available in the current environment, as opposed to the
+// credential being rejected by Azure AD. Callers should treat this differently from IsAzureAPIUnauthorized: retrying
+// an unavailable-credential error will not help until the environment is fixed (e.g. the federated token file or the
+// IMDS endpoint is missing), whereas an unauthorized error may be transient.
+func IsAzureAPICredentialUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	// azidentity's credentialUnavailableError is unexported, so it can't be targeted with errors.As. It is a type of
+	// its own, distinct from *azidentity.AuthenticationFailedError, and is recognised here by the message explaining
+	// which credential in the chain could not be used - this is the most stable way to recognise it across SDK versions.
+	return strings.Contains(err.Error(), "no credential in this chain provided a token") ||
+		strings.Contains(err.Error(), "credential unavailable")
 }
 
 // CloudConfiguration returns a CloudConfiguration for the given input, prioritising the given CloudConfiguration if both inputs are not nil. In essence
@@ -115,12 +147,40 @@ func AzureCloudConfigurationFromCloudConfiguration(cloudConfiguration *azure.Clo
 		return cloud.AzureGovernment, nil
 	case strings.EqualFold(cloudConfigurationName, azure.AzureChinaCloudName):
 		return cloud.AzureChina, nil
+	case strings.EqualFold(cloudConfigurationName, azure.AzureStackCloudName):
+		return cloudConfigurationFromCustomCloud(cloudConfiguration)
 
 	default:
 		return cloud.Configuration{}, fmt.Errorf("unknown cloud configuration name '%s'", cloudConfigurationName)
 	}
 }
 
+// cloudConfigurationFromCustomCloud builds a cloud.Configuration from the explicit endpoint overrides of a custom
+// (e.g. Azure Stack Hub) CloudConfiguration, since the Azure SDK has no well-known entry for these.
+func cloudConfigurationFromCustomCloud(cloudConfiguration *azure.CloudConfiguration) (cloud.Configuration, error) {
+	if cloudConfiguration.ActiveDirectoryAuthorityHost == nil || cloudConfiguration.ResourceManagerEndpoint == nil {
+		return cloud.Configuration{}, fmt.Errorf("activeDirectoryAuthorityHost and resourceManagerEndpoint must be set for cloud configuration %q", cloudConfiguration.Name)
+	}
+
+	services := map[cloud.ServiceName]cloud.ServiceConfiguration{
+		cloud.ResourceManager: {
+			Audience: *cloudConfiguration.ResourceManagerEndpoint,
+			Endpoint: *cloudConfiguration.ResourceManagerEndpoint,
+		},
+	}
+	for _, svc := range cloudConfiguration.ServiceConfiguration {
+		services[cloud.ServiceName(svc.Name)] = cloud.ServiceConfiguration{
+			Audience: svc.Audience,
+			Endpoint: svc.Endpoint,
+		}
+	}
+
+	return cloud.Configuration{
+		ActiveDirectoryAuthorityHost: *cloudConfiguration.ActiveDirectoryAuthorityHost,
+		Services:                     services,
+	}, nil
+}
+
 // AzureCloudEnvVarName is a convenience function to get the correct env-var name to use in terraform for the given input,
 // preferring the cloudConfiguration if both values are not nil.
 func AzureCloudEnvVarName(cloudConfiguration *azure.CloudConfiguration, region *string) (string, error) {
@@ -148,6 +208,9 @@ func cloudEnvVarNameFromCloudConfiguration(cloudConfiguration *azure.CloudConfig
 		return "AZUREUSGOVERNMENT", nil
 	case strings.EqualFold(cloudConfigurationName, "AzureChina"):
 		return "AZURECHINACLOUD", nil
+	case strings.EqualFold(cloudConfigurationName, azure.AzureStackCloudName):
+		// AZURESTACKCLOUD is the env-var value the CCM/CSI Azure Stack Hub cloud providers expect.
+		return "AZURESTACKCLOUD", nil
 	default:
 		return "", fmt.Errorf("unknown cloud configuration name '%s'", cloudConfigurationName)
 	}
@@ -0,0 +1,275 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+
+	apisazure "github.com/gardener/gardener-extension-provider-azure/pkg/apis/azure"
+)
+
+// StorageAccount is an interface for the Azure storage account service.
+type StorageAccount interface {
+	// CreateStorageAccount creates a new storage account with the given name in the given resource group and region.
+	// requireInfrastructureEncryption, if true, additionally enables platform-managed encryption underneath any
+	// customer-managed key configured via EnsureEncryption; it can only be set at creation time.
+	CreateStorageAccount(ctx context.Context, resourceGroupName, storageAccountName, region string, requireInfrastructureEncryption bool) error
+	// ListStorageAccountKey returns a usable access key of the given storage account.
+	ListStorageAccountKey(ctx context.Context, resourceGroupName, storageAccountName string) (string, error)
+	// RotateKey regenerates the storage account key that is currently not equal to the given (active) key and returns it.
+	RotateKey(ctx context.Context, resourceGroupName, storageAccountName, storageAccountKey string) (string, error)
+	// EnsureImmutability enables blob versioning, change-feed and the given retention policy on the storage account's
+	// default blob container, so that objects written to it can no longer be deleted or overwritten before the
+	// retention period expires.
+	EnsureImmutability(ctx context.Context, resourceGroupName, storageAccountName string, immutability *apisazure.ImmutableConfig) error
+	// EnsureEncryption configures the storage account to encrypt data at rest with the customer-managed key
+	// described by encryption, re-applying the key version on every call so that key rotations in Key Vault are
+	// picked up on the next reconcile.
+	EnsureEncryption(ctx context.Context, resourceGroupName, storageAccountName string, encryption *apisazure.EncryptionConfig) error
+}
+
+type storageAccount struct {
+	accountsClient     *armstorage.AccountsClient
+	blobServicesClient *armstorage.BlobServicesClient
+	containersClient   *armstorage.BlobContainersClient
+}
+
+// NewStorageAccountClient creates a new StorageAccount client.
+func NewStorageAccountClient(accountsClient *armstorage.AccountsClient, blobServicesClient *armstorage.BlobServicesClient, containersClient *armstorage.BlobContainersClient) StorageAccount {
+	return &storageAccount{
+		accountsClient:     accountsClient,
+		blobServicesClient: blobServicesClient,
+		containersClient:   containersClient,
+	}
+}
+
+// CreateStorageAccount creates a new storage account with the given name in the given resource group and region.
+func (s *storageAccount) CreateStorageAccount(ctx context.Context, resourceGroupName, storageAccountName, region string, requireInfrastructureEncryption bool) error {
+	properties := &armstorage.AccountPropertiesCreateParameters{}
+	if requireInfrastructureEncryption {
+		properties.Encryption = &armstorage.Encryption{
+			RequireInfrastructureEncryption: to.Ptr(true),
+			KeySource:                       to.Ptr(armstorage.KeySourceMicrosoftStorage),
+		}
+	}
+
+	poller, err := s.accountsClient.BeginCreate(ctx, resourceGroupName, storageAccountName, armstorage.AccountCreateParameters{
+		Location:   to.Ptr(region),
+		SKU:        &armstorage.SKU{Name: to.Ptr(armstorage.SKUNameStandardLRS)},
+		Kind:       to.Ptr(armstorage.KindStorageV2),
+		Properties: properties,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	_, err = poller.PollUntilDone(ctx, nil)
+	return err
+}
+
+// ListStorageAccountKey returns the first usable access key of the given storage account.
+func (s *storageAccount) ListStorageAccountKey(ctx context.Context, resourceGroupName, storageAccountName string) (string, error) {
+	res, err := s.accountsClient.ListKeys(ctx, resourceGroupName, storageAccountName, nil)
+	if err != nil {
+		return "", err
+	}
+	if len(res.Keys) == 0 || res.Keys[0].Value == nil {
+		return "", fmt.Errorf("storage account %q has no usable keys", storageAccountName)
+	}
+	return *res.Keys[0].Value, nil
+}
+
+// RotateKey regenerates the storage account key that is currently not equal to the given (active) key and returns it.
+func (s *storageAccount) RotateKey(ctx context.Context, resourceGroupName, storageAccountName, storageAccountKey string) (string, error) {
+	keys, err := s.accountsClient.ListKeys(ctx, resourceGroupName, storageAccountName, nil)
+	if err != nil {
+		return "", err
+	}
+	var keyName string
+	for _, key := range keys.Keys {
+		if key.KeyName != nil && key.Value != nil && *key.Value != storageAccountKey {
+			keyName = *key.KeyName
+			break
+		}
+	}
+	if keyName == "" {
+		return "", fmt.Errorf("storage account %q has no inactive key to rotate", storageAccountName)
+	}
+
+	res, err := s.accountsClient.RegenerateKey(ctx, resourceGroupName, storageAccountName, armstorage.AccountRegenerateKeyParameters{
+		KeyName: to.Ptr(keyName),
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	for _, key := range res.Keys {
+		if key.KeyName != nil && *key.KeyName == keyName && key.Value != nil {
+			return *key.Value, nil
+		}
+	}
+	return "", fmt.Errorf("storage account %q did not return a rotated key", storageAccountName)
+}
+
+// EnsureImmutability enables versioning, change-feed and a time-based retention policy on the storage account's
+// default blob container so that backups written to it become tamper-proof (WORM). It does not set a legal hold.
+//
+// Rotating the storage account's access keys (see Factory.StorageAccount().RotateKey) never touches the
+// container's immutability policy - that state lives on the container/blob-service resources, not on the keys -
+// so callers can safely rotate credentials without risking the retention guarantees configured here.
+func (s *storageAccount) EnsureImmutability(ctx context.Context, resourceGroupName, storageAccountName string, immutability *apisazure.ImmutableConfig) error {
+	if immutability == nil {
+		return nil
+	}
+	if err := validateImmutableConfig(immutability); err != nil {
+		return err
+	}
+
+	if _, err := s.blobServicesClient.SetServiceProperties(ctx, resourceGroupName, storageAccountName, armstorage.BlobServiceProperties{
+		BlobServicePropertiesProperties: &armstorage.BlobServicePropertiesProperties{
+			IsVersioningEnabled: to.Ptr(true),
+			ChangeFeed: &armstorage.ChangeFeed{
+				Enabled: to.Ptr(true),
+			},
+		},
+	}, nil); err != nil {
+		return err
+	}
+
+	// The default container is usually only created later, on the data plane, by the etcd-backup-restore sidecar
+	// once it has credentials for the storage account. Create it here too so the immutability policy below always
+	// has a container to attach to on the very first reconcile of a brand-new, immutable bucket.
+	if _, err := s.containersClient.Create(ctx, resourceGroupName, storageAccountName, defaultContainerName, armstorage.BlobContainer{}, nil); err != nil && !IsAzureAPIConflict(err) {
+		return err
+	}
+
+	existing, err := s.containersClient.GetImmutabilityPolicy(ctx, resourceGroupName, storageAccountName, defaultContainerName, nil)
+	if err != nil && !IsAzureAPINotFoundError(err) {
+		return err
+	}
+	alreadyLocked := err == nil && existing.ImmutabilityPolicyProperty != nil &&
+		existing.State != nil && *existing.State == armstorage.ImmutabilityPolicyStateLocked
+
+	if alreadyLocked {
+		// Once locked, Azure rejects CreateOrUpdateImmutabilityPolicy against the container (ExtendImmutabilityPolicy
+		// would be required to change the retention period instead), so blindly re-PUTting here would permanently
+		// break reconciliation for every bucket that has already been locked. Nothing to do if it already matches
+		// the desired retention; otherwise surface that it can no longer be changed this way.
+		if existing.ImmutabilityPeriodSinceCreationInDays != nil && *existing.ImmutabilityPeriodSinceCreationInDays == immutability.RetentionDays {
+			return nil
+		}
+		return fmt.Errorf("storage account %q has a locked immutability policy with a retention period of %d days, which cannot be changed to the requested %d days", storageAccountName, *existing.ImmutabilityPeriodSinceCreationInDays, immutability.RetentionDays)
+	}
+
+	policy, err := s.containersClient.CreateOrUpdateImmutabilityPolicy(ctx, resourceGroupName, storageAccountName, defaultContainerName, armstorage.ImmutabilityPolicy{
+		ImmutabilityPolicyProperty: &armstorage.ImmutabilityPolicyProperty{
+			ImmutabilityPeriodSinceCreationInDays: to.Ptr(immutability.RetentionDays),
+			AllowProtectedAppendWrites:            to.Ptr(false),
+		},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	if !immutability.Locked {
+		return nil
+	}
+
+	// Locking is irreversible: once locked, the retention period can only ever be extended, never shortened or
+	// removed, which is the whole point of requesting it for regulatory WORM compliance. Without this call,
+	// CreateOrUpdateImmutabilityPolicy above leaves the policy in its default, still-mutable state.
+	if policy.Etag == nil {
+		return fmt.Errorf("storage account %q did not return an ETag for its immutability policy, cannot lock it", storageAccountName)
+	}
+	_, err = s.containersClient.LockImmutabilityPolicy(ctx, resourceGroupName, storageAccountName, defaultContainerName, *policy.Etag, nil)
+	return err
+}
+
+// validateImmutableConfig rejects ImmutableConfig values this client doesn't know how to apply, rather than
+// silently ignoring fields it doesn't act on.
+func validateImmutableConfig(immutability *apisazure.ImmutableConfig) error {
+	if immutability.RetentionType != retentionTypeBucket {
+		return fmt.Errorf("unsupported immutability retention type %q, only %q is supported", immutability.RetentionType, retentionTypeBucket)
+	}
+	return nil
+}
+
+// EnsureEncryption configures the storage account to use the customer-managed key described by encryption,
+// switching the account's key source to Microsoft.Keyvault and wiring up the identity allowed to access it. Calling
+// this again with a new key version (e.g. after a rotation in Key Vault) re-applies it, since the Azure SDK always
+// sends the full desired KeyVaultProperties on update.
+func (s *storageAccount) EnsureEncryption(ctx context.Context, resourceGroupName, storageAccountName string, encryption *apisazure.EncryptionConfig) error {
+	if encryption == nil {
+		return nil
+	}
+
+	vaultURI, keyName, keyVersion, err := parseKeyVaultKeyURI(encryption.KeyVaultKeyURI)
+	if err != nil {
+		return err
+	}
+
+	identity := &armstorage.Identity{Type: to.Ptr(armstorage.IdentityTypeSystemAssigned)}
+	userAssignedIdentity := (*string)(nil)
+	if encryption.UserAssignedIdentityID != nil {
+		identity = &armstorage.Identity{
+			Type:                   to.Ptr(armstorage.IdentityTypeUserAssigned),
+			UserAssignedIdentities: map[string]*armstorage.UserAssignedIdentity{*encryption.UserAssignedIdentityID: {}},
+		}
+		userAssignedIdentity = encryption.UserAssignedIdentityID
+	}
+
+	keyVaultProperties := &armstorage.KeyVaultProperties{
+		KeyVaultURI:          to.Ptr(vaultURI),
+		KeyName:              to.Ptr(keyName),
+		UserAssignedIdentity: userAssignedIdentity,
+	}
+	if keyVersion != "" {
+		keyVaultProperties.KeyVersion = to.Ptr(keyVersion)
+	}
+
+	_, err = s.accountsClient.Update(ctx, resourceGroupName, storageAccountName, armstorage.AccountUpdateParameters{
+		Identity: identity,
+		Properties: &armstorage.AccountPropertiesUpdateParameters{
+			Encryption: &armstorage.Encryption{
+				KeySource:          to.Ptr(armstorage.KeySourceMicrosoftKeyvault),
+				KeyVaultProperties: keyVaultProperties,
+			},
+		},
+	}, nil)
+	return err
+}
+
+// parseKeyVaultKeyURI splits a Key Vault key URI, e.g.
+// "https://my-vault.vault.azure.net/keys/my-key/abcdef0123456789abcdef0123456789", into the vault's base URI, the
+// key name, and the (optional) key version ARM's Microsoft.Storage encryption schema expects as separate fields -
+// unlike Key Vault's own APIs, which accept the combined URI as a single value.
+func parseKeyVaultKeyURI(keyVaultKeyURI string) (vaultURI, keyName, keyVersion string, err error) {
+	u, err := url.Parse(keyVaultKeyURI)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to parse Key Vault key URI %q: %w", keyVaultKeyURI, err)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] != "keys" || segments[1] == "" {
+		return "", "", "", fmt.Errorf("Key Vault key URI %q must be of the form https://<vault>/keys/<name>[/<version>]", keyVaultKeyURI)
+	}
+
+	keyName = segments[1]
+	if len(segments) > 2 {
+		keyVersion = segments[2]
+	}
+
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host), keyName, keyVersion, nil
+}
+
+const defaultContainerName = "backup"
+
+// retentionTypeBucket is the only apisazure.ImmutableConfig.RetentionType this client currently implements: the
+// retention policy applies to the storage account's default backup container as a whole.
+const retentionTypeBucket = "bucket"
@@ -0,0 +1,105 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"testing"
+
+	apisazure "github.com/gardener/gardener-extension-provider-azure/pkg/apis/azure"
+)
+
+func TestValidateImmutableConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *apisazure.ImmutableConfig
+		wantErr bool
+	}{
+		{
+			name:   "supported retention type",
+			config: &apisazure.ImmutableConfig{RetentionType: "bucket", RetentionDays: 30},
+		},
+		{
+			name:    "unsupported retention type",
+			config:  &apisazure.ImmutableConfig{RetentionType: "object", RetentionDays: 30},
+			wantErr: true,
+		},
+		{
+			name:    "empty retention type",
+			config:  &apisazure.ImmutableConfig{RetentionDays: 30},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImmutableConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateImmutableConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseKeyVaultKeyURI(t *testing.T) {
+	tests := []struct {
+		name            string
+		keyVaultKeyURI  string
+		wantVaultURI    string
+		wantKeyName     string
+		wantKeyVersion  string
+		wantErr         bool
+	}{
+		{
+			name:           "versioned key",
+			keyVaultKeyURI: "https://my-vault.vault.azure.net/keys/my-key/abcdef0123456789abcdef0123456789",
+			wantVaultURI:   "https://my-vault.vault.azure.net",
+			wantKeyName:    "my-key",
+			wantKeyVersion: "abcdef0123456789abcdef0123456789",
+		},
+		{
+			name:           "unversioned key",
+			keyVaultKeyURI: "https://my-vault.vault.azure.net/keys/my-key",
+			wantVaultURI:   "https://my-vault.vault.azure.net",
+			wantKeyName:    "my-key",
+			wantKeyVersion: "",
+		},
+		{
+			name:           "not a key URI",
+			keyVaultKeyURI: "https://my-vault.vault.azure.net/secrets/my-secret",
+			wantErr:        true,
+		},
+		{
+			name:           "missing key name",
+			keyVaultKeyURI: "https://my-vault.vault.azure.net/keys/",
+			wantErr:        true,
+		},
+		{
+			name:           "malformed URI",
+			keyVaultKeyURI: "://not a uri",
+			wantErr:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaultURI, keyName, keyVersion, err := parseKeyVaultKeyURI(tt.keyVaultKeyURI)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKeyVaultKeyURI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if vaultURI != tt.wantVaultURI {
+				t.Errorf("vaultURI = %q, want %q", vaultURI, tt.wantVaultURI)
+			}
+			if keyName != tt.wantKeyName {
+				t.Errorf("keyName = %q, want %q", keyName, tt.wantKeyName)
+			}
+			if keyVersion != tt.wantKeyVersion {
+				t.Errorf("keyVersion = %q, want %q", keyVersion, tt.wantKeyVersion)
+			}
+		})
+	}
+}
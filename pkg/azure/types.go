@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package azure
+
+// Generated backup bucket secret data keys.
+const (
+	// StorageAccount is the key for the storage account name in the backup bucket's generated secret.
+	StorageAccount = "storageAccount"
+	// StorageKey is the key for the storage account access key in the backup bucket's generated secret.
+	StorageKey = "storageKey"
+	// StorageDomain is the key for the storage service domain in the backup bucket's generated secret.
+	StorageDomain = "domain"
+	// TenantID is the key for the Azure AD tenant ID in the backup bucket's generated secret, used when the
+	// etcd-backup-restore sidecar authenticates via Workload Identity or a user-assigned managed identity instead
+	// of a storage account key.
+	TenantID = "tenantID"
+	// ClientID is the key for the Azure AD application (client) ID in the backup bucket's generated secret, used
+	// together with TenantID when authenticating via Workload Identity or a user-assigned managed identity.
+	ClientID = "clientID"
+)
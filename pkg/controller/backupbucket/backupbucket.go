@@ -13,6 +13,7 @@ import (
 	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
 	"github.com/gardener/gardener/pkg/utils"
 
+	apisazure "github.com/gardener/gardener-extension-provider-azure/pkg/apis/azure"
 	azureclient "github.com/gardener/gardener-extension-provider-azure/pkg/azure/client"
 )
 
@@ -20,7 +21,10 @@ func getStorageAccountName(backupBucket *extensionsv1alpha1.BackupBucket) string
 	return fmt.Sprintf("bkp%s", utils.ComputeSHA256Hex([]byte(backupBucket.Name))[:15])
 }
 
-func ensureBackupBucket(ctx context.Context, factory azureclient.Factory, backupBucket *extensionsv1alpha1.BackupBucket) (string, string, error) {
+// ensureBackupBucket creates (or updates) the storage account backing backupBucket and, once a key-based generated
+// secret for it exists, drives any due storage-account key rotation via EnsureStorageAccountKeyRotation - rotation is
+// a no-op until createBackupBucketGeneratedSecret has run at least once, since there is nothing to rotate yet.
+func (a *actuator) ensureBackupBucket(ctx context.Context, factory azureclient.Factory, backupBucket *extensionsv1alpha1.BackupBucket, config *apisazure.BackupBucketConfig, identity *azureclient.WorkloadIdentity) (string, string, error) {
 	storageAccountName := getStorageAccountName(backupBucket)
 
 	// Get resource group client to ensure resource group to host backup storage account exists.
@@ -28,8 +32,11 @@ func ensureBackupBucket(ctx context.Context, factory azureclient.Factory, backup
 	if err != nil {
 		return "", "", err
 	}
-	if _, err := groupClient.CreateOrUpdate(ctx, backupBucket.Name, armresources.ResourceGroup{
-		Location: to.Ptr(backupBucket.Spec.Region),
+	if err := withRetry(ctx, func() error {
+		_, err := groupClient.CreateOrUpdate(ctx, backupBucket.Name, armresources.ResourceGroup{
+			Location: to.Ptr(backupBucket.Spec.Region),
+		})
+		return err
 	}); err != nil {
 		return "", "", err
 	}
@@ -39,19 +46,67 @@ func ensureBackupBucket(ctx context.Context, factory azureclient.Factory, backup
 	if err != nil {
 		return "", "", err
 	}
-	if err := storageAccountClient.CreateStorageAccount(ctx, backupBucket.Name, storageAccountName, backupBucket.Spec.Region); err != nil {
+	requireInfrastructureEncryption := config != nil && config.Encryption != nil && config.Encryption.RequireInfrastructureEncryption
+	if err := withRetry(ctx, func() error {
+		return storageAccountClient.CreateStorageAccount(ctx, backupBucket.Name, storageAccountName, backupBucket.Spec.Region, requireInfrastructureEncryption)
+	}); err != nil {
 		return "", "", err
 	}
 
+	if config != nil && config.Immutability != nil {
+		if err := withRetry(ctx, func() error {
+			return storageAccountClient.EnsureImmutability(ctx, backupBucket.Name, storageAccountName, config.Immutability)
+		}); err != nil {
+			return "", "", fmt.Errorf("failed to ensure immutability for storage account %q: %w", storageAccountName, err)
+		}
+	}
+
+	if config != nil && config.Encryption != nil {
+		if err := withRetry(ctx, func() error {
+			return storageAccountClient.EnsureEncryption(ctx, backupBucket.Name, storageAccountName, config.Encryption)
+		}); err != nil {
+			return "", "", wrapEncryptionError(storageAccountName, err)
+		}
+	}
+
+	// When the sidecar authenticates via Workload Identity or a managed identity, it talks to Blob storage directly
+	// and never needs a storage-account key - retrieving and handing one out would only widen the credential's
+	// blast radius for no benefit.
+	if identity != nil {
+		return storageAccountName, "", nil
+	}
+
 	// Get a key of the storage account. We simply use the first one for new storage accounts (later rotations may change the "current" one).
-	storageAccountKey, err := storageAccountClient.ListStorageAccountKey(ctx, backupBucket.Name, storageAccountName)
-	if err != nil {
+	var storageAccountKey string
+	if err := withRetry(ctx, func() error {
+		var err error
+		storageAccountKey, err = storageAccountClient.ListStorageAccountKey(ctx, backupBucket.Name, storageAccountName)
+		return err
+	}); err != nil {
 		return "", "", err
 	}
 
+	if err := a.EnsureStorageAccountKeyRotation(ctx, factory, backupBucket, "reconcile"); err != nil {
+		return "", "", fmt.Errorf("failed to ensure storage account key rotation for storage account %q: %w", storageAccountName, err)
+	}
+
 	return storageAccountName, storageAccountKey, nil
 }
 
+// wrapEncryptionError annotates a failure from EnsureEncryption with enough context to act on it. In particular, it
+// calls out a Key Vault access-denied error explicitly, since fixing that requires granting the storage account's
+// identity access to the key in Key Vault, whereas the generic message would leave an operator debugging the wrong
+// side of the integration.
+func wrapEncryptionError(storageAccountName string, err error) error {
+	if azureclient.IsAzureAPIKeyVaultAccessDenied(err) {
+		return fmt.Errorf("storage account %q was denied access to the configured Key Vault key: grant the storage account's identity access to the key: %w", storageAccountName, err)
+	}
+	return fmt.Errorf("failed to ensure customer-managed encryption for storage account %q: %w", storageAccountName, err)
+}
+
+// rotateStorageAccountCredentials regenerates the storage account key that is not currently in use and returns it.
+// Key rotation only touches the storage account's access keys, never the immutability policy configured by
+// ensureBackupBucket, so rotating credentials is always safe to perform regardless of any retention policy in place.
 func rotateStorageAccountCredentials(
 	ctx context.Context,
 	factory azureclient.Factory,
@@ -67,5 +122,14 @@ func rotateStorageAccountCredentials(
 	if err != nil {
 		return "", err
 	}
-	return storageAccountClient.RotateKey(ctx, resourceGroupName, storageAccountName, storageAccountKey)
+
+	var rotatedKey string
+	if err := withRetry(ctx, func() error {
+		var err error
+		rotatedKey, err = storageAccountClient.RotateKey(ctx, resourceGroupName, storageAccountName, storageAccountKey)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	return rotatedKey, nil
 }
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backupbucket
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+func TestWrapEncryptionError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantMsg string
+	}{
+		{
+			name:    "key vault access denied",
+			err:     &azcore.ResponseError{StatusCode: http.StatusForbidden, ErrorCode: "KeyVaultAccessDenied"},
+			wantMsg: "denied access to the configured Key Vault key",
+		},
+		{
+			name:    "generic failure",
+			err:     errors.New("boom"),
+			wantMsg: "failed to ensure customer-managed encryption",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapEncryptionError("mystorageaccount", tt.err)
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("wrapEncryptionError() = %q, want it to contain %q", err.Error(), tt.wantMsg)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("wrapEncryptionError() does not wrap the original error")
+			}
+		})
+	}
+}
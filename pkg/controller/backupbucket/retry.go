@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backupbucket
+
+import (
+	"context"
+	"time"
+
+	azureclient "github.com/gardener/gardener-extension-provider-azure/pkg/azure/client"
+)
+
+// maxRetrySteps bounds how many times withRetry calls fn (the first attempt included) before giving up and returning
+// its last error.
+const maxRetrySteps = 5
+
+// withRetry calls fn, retrying with exponential backoff on errors that the Azure error classifiers (see
+// pkg/azure/client/errors.go) identify as safe to retry - throttling (honoring any Retry-After the API sent),
+// transient 5xx/network failures, and a concurrent operation already in progress on the same resource. Any other
+// error, notably a genuine conflict, quota or permission error, is returned immediately so the reconcile can
+// surface it instead of masking it behind retries that can never succeed. Once retries are exhausted, the last
+// error seen is returned so callers (and the BackupBucket status) still see the real failure reason.
+func withRetry(ctx context.Context, fn func() error) error {
+	backoff := time.Second
+
+	var err error
+	for step := 0; step < maxRetrySteps; step++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+
+		var wait time.Duration
+		switch {
+		case azureclient.IsAzureAPIThrottled(err):
+			// The server told us exactly how long to back off; honor that instead of our own backoff step.
+			if d, ok := azureclient.RetryAfter(err); ok {
+				wait = d
+				break
+			}
+			wait = backoff
+		case azureclient.IsAzureAPIAnotherOperationInProgress(err), azureclient.IsAzureAPITransient(err):
+			wait = backoff
+		case azureclient.IsAzureAPIConflict(err), azureclient.IsAzureAPIQuotaExceeded(err):
+			// Neither a genuine conflict nor an exhausted quota resolves itself by waiting, so surface it
+			// immediately instead of burning through retries that can never succeed.
+			return err
+		default:
+			return err
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+
+	return err
+}
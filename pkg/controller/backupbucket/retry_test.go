@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backupbucket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+)
+
+func TestWithRetryNonRetryableErrorsFailFast(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"conflict", &azcore.ResponseError{StatusCode: http.StatusConflict, ErrorCode: "Conflict"}},
+		{"quota exceeded", &azcore.ResponseError{ErrorCode: "SubscriptionQuotaExceeded"}},
+		{"plain local error", errors.New("storage account has no usable keys")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := withRetry(context.Background(), func() error {
+				calls++
+				return tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("withRetry() error = %v, want %v", err, tt.err)
+			}
+			if calls != 1 {
+				t.Errorf("fn called %d times, want exactly 1 (no retry)", calls)
+			}
+		})
+	}
+}
+
+func TestWithRetryRetriesTransientErrors(t *testing.T) {
+	// Canceling the context up front makes withRetry's select between the backoff timer and ctx.Done() resolve to
+	// ctx.Done() without actually waiting out a real backoff, so this stays fast while still exercising the retry
+	// branch (as opposed to the fail-fast branch exercised above).
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	transientErr := &azcore.ResponseError{StatusCode: http.StatusInternalServerError}
+	err := withRetry(ctx, func() error {
+		calls++
+		return transientErr
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("withRetry() error = %v, want context.Canceled", err)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want exactly 1", calls)
+	}
+}
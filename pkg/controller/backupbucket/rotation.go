@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backupbucket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	gardencorev1beta1helper "github.com/gardener/gardener/pkg/apis/core/v1beta1/helper"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	"github.com/gardener/gardener/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/gardener/gardener-extension-provider-azure/pkg/azure"
+	azureclient "github.com/gardener/gardener-extension-provider-azure/pkg/azure/client"
+)
+
+const (
+	// keyRotationAcknowledgedCondition is set on the core BackupBucket resource by the etcd-backup-restore sidecar
+	// once it has picked up the freshly rotated (inactive) storage account key from the generated secret. Only once
+	// this condition is True is it safe to regenerate the previously active key, since doing so any earlier could
+	// invalidate the key an in-flight backup upload is still using.
+	keyRotationAcknowledgedCondition gardencorev1beta1.ConditionType = "KeyRotationAcknowledged"
+
+	// Annotations recording the rotation history on the generated secret, so operators can audit when a rotation
+	// happened, which key was touched, and what initiated it.
+	rotationTimestampAnnotation = "backupbucket.azure.provider.extensions.gardener.cloud/rotated-at"
+	rotationKeyNameAnnotation   = "backupbucket.azure.provider.extensions.gardener.cloud/rotated-key"
+	rotationInitiatorAnnotation = "backupbucket.azure.provider.extensions.gardener.cloud/rotation-initiator"
+)
+
+// EnsureStorageAccountKeyRotation drives the two-phase key rotation to completion across reconciles: once the
+// active key is old enough (shouldBeRotated), it regenerates the inactive key and waits for the etcd-backup-restore
+// sidecar to acknowledge it via keyRotationAcknowledgedCondition before regenerating the key that was previously
+// active. It is a no-op for backup buckets that authenticate via Workload Identity, since those never hold a
+// storage-account key in the first place.
+func (a *actuator) EnsureStorageAccountKeyRotation(ctx context.Context, factory azureclient.Factory, backupBucket *extensionsv1alpha1.BackupBucket, initiator string) error {
+	secret, err := a.getBackupBucketGeneratedSecret(ctx, backupBucket)
+	if err != nil {
+		return err
+	}
+	if secret == nil || len(secret.Data[azure.StorageKey]) == 0 {
+		return nil
+	}
+
+	if a.isKeyRotationAcknowledged(backupBucket) {
+		return a.finalizeStorageAccountKeyRotation(ctx, factory, backupBucket, secret, initiator)
+	}
+
+	if a.isKeyRotationPending(backupBucket) {
+		// Already waiting for the sidecar to pick up the inactive key - regenerating the active key now would
+		// reintroduce the very race this rotation scheme exists to avoid.
+		return nil
+	}
+
+	due, err := shouldBeRotated(*secret)
+	if err != nil {
+		return err
+	}
+	if !due {
+		return nil
+	}
+
+	return a.beginStorageAccountKeyRotation(ctx, factory, backupBucket, secret, initiator)
+}
+
+// beginStorageAccountKeyRotation regenerates the storage account key that is currently *not* referenced by the
+// generated secret ("the inactive key"), publishes it to the generated secret, and records the rotation in the
+// secret's annotations for auditing. It does not touch the key that is still active, so any backup upload that is
+// currently in flight keeps working. The caller is expected to wait for keyRotationAcknowledgedCondition to become
+// True before calling finalizeStorageAccountKeyRotation.
+func (a *actuator) beginStorageAccountKeyRotation(ctx context.Context, factory azureclient.Factory, backupBucket *extensionsv1alpha1.BackupBucket, secret *corev1.Secret, initiator string) error {
+	activeKey := string(secret.Data[azure.StorageKey])
+
+	rotatedKey, err := rotateStorageAccountCredentials(ctx, factory, backupBucket, activeKey)
+	if err != nil {
+		return fmt.Errorf("failed to rotate inactive storage account key: %w", err)
+	}
+
+	if err := a.patchGeneratedSecretData(ctx, secret, rotatedKey, initiator); err != nil {
+		return err
+	}
+
+	if err := a.setKeyRotationAcknowledgedCondition(ctx, backupBucket, gardencorev1beta1.ConditionFalse, "RotationPending", "waiting for etcd-backup-restore to acknowledge the rotated storage account key"); err != nil {
+		return err
+	}
+
+	a.recorder.Eventf(backupBucket, corev1.EventTypeNormal, "StorageAccountKeyRotated", "rotated inactive storage account key (initiator: %s)", initiator)
+	return nil
+}
+
+// finalizeStorageAccountKeyRotation regenerates the key that was active before beginStorageAccountKeyRotation, now
+// that the sidecar has acknowledged the new key via keyRotationAcknowledgedCondition. This completes the rotation:
+// the old key, which may have leaked or simply aged out, stops working.
+func (a *actuator) finalizeStorageAccountKeyRotation(ctx context.Context, factory azureclient.Factory, backupBucket *extensionsv1alpha1.BackupBucket, secret *corev1.Secret, initiator string) error {
+	if !a.isKeyRotationAcknowledged(backupBucket) {
+		return fmt.Errorf("key rotation for backup bucket %q has not been acknowledged yet, refusing to finalize", backupBucket.Name)
+	}
+
+	currentKey := string(secret.Data[azure.StorageKey])
+
+	rotatedKey, err := rotateStorageAccountCredentials(ctx, factory, backupBucket, currentKey)
+	if err != nil {
+		return fmt.Errorf("failed to rotate previously active storage account key: %w", err)
+	}
+
+	if err := a.patchGeneratedSecretData(ctx, secret, rotatedKey, initiator); err != nil {
+		return err
+	}
+
+	if err := a.setKeyRotationAcknowledgedCondition(ctx, backupBucket, gardencorev1beta1.ConditionUnknown, "RotationCompleted", "storage account key rotation completed"); err != nil {
+		return err
+	}
+
+	a.recorder.Eventf(backupBucket, corev1.EventTypeNormal, "StorageAccountKeyRotationCompleted", "rotated previously active storage account key (initiator: %s)", initiator)
+	return nil
+}
+
+// patchGeneratedSecretData updates the generated secret's storage key and stamps the rotation audit annotations.
+func (a *actuator) patchGeneratedSecretData(ctx context.Context, secret *corev1.Secret, rotatedKey, initiator string) error {
+	patch := client.MergeFrom(secret.DeepCopy())
+	secret.Data[azure.StorageKey] = []byte(rotatedKey)
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[rotationTimestampAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	// Never store (a portion of) the actual key in an annotation - a hash is enough to tell operators that a
+	// rotation actually changed the key, without widening the key's exposure.
+	secret.Annotations[rotationKeyNameAnnotation] = utils.ComputeSHA256Hex([]byte(rotatedKey))[:8]
+	secret.Annotations[rotationInitiatorAnnotation] = initiator
+	return a.client.Patch(ctx, secret, patch)
+}
+
+// isKeyRotationAcknowledged reports whether the etcd-backup-restore sidecar has acknowledged the currently rotated
+// storage account key via keyRotationAcknowledgedCondition.
+func (a *actuator) isKeyRotationAcknowledged(backupBucket *extensionsv1alpha1.BackupBucket) bool {
+	condition := gardencorev1beta1helper.GetCondition(backupBucket.Status.Conditions, keyRotationAcknowledgedCondition)
+	return condition != nil && condition.Status == gardencorev1beta1.ConditionTrue
+}
+
+// isKeyRotationPending reports whether beginStorageAccountKeyRotation has regenerated the inactive key and is now
+// waiting for the etcd-backup-restore sidecar to acknowledge it.
+func (a *actuator) isKeyRotationPending(backupBucket *extensionsv1alpha1.BackupBucket) bool {
+	condition := gardencorev1beta1helper.GetCondition(backupBucket.Status.Conditions, keyRotationAcknowledgedCondition)
+	return condition != nil && condition.Status == gardencorev1beta1.ConditionFalse
+}
+
+// setKeyRotationAcknowledgedCondition updates keyRotationAcknowledgedCondition on the core BackupBucket resource.
+func (a *actuator) setKeyRotationAcknowledgedCondition(ctx context.Context, backupBucket *extensionsv1alpha1.BackupBucket, status gardencorev1beta1.ConditionStatus, reason, message string) error {
+	patch := client.MergeFrom(backupBucket.DeepCopy())
+	backupBucket.Status.Conditions = gardencorev1beta1helper.MergeConditions(
+		backupBucket.Status.Conditions,
+		gardencorev1beta1helper.UpdatedConditionWithClock(clock.RealClock{}, gardencorev1beta1helper.GetOrInitConditionWithClock(clock.RealClock{}, backupBucket.Status.Conditions, keyRotationAcknowledgedCondition), status, reason, message),
+	)
+	return a.client.Status().Patch(ctx, backupBucket, patch)
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2024 SAP SE or an SAP affiliate company and Gardener contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package backupbucket
+
+import (
+	"testing"
+	"time"
+
+	gardencorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	extensionsv1alpha1 "github.com/gardener/gardener/pkg/apis/extensions/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func backupBucketWithCondition(status gardencorev1beta1.ConditionStatus) *extensionsv1alpha1.BackupBucket {
+	return &extensionsv1alpha1.BackupBucket{
+		Status: extensionsv1alpha1.BackupBucketStatus{
+			DefaultStatus: extensionsv1alpha1.DefaultStatus{
+				Conditions: []gardencorev1beta1.Condition{
+					{
+						Type:   keyRotationAcknowledgedCondition,
+						Status: status,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestIsKeyRotationAcknowledged(t *testing.T) {
+	a := &actuator{}
+
+	tests := []struct {
+		name         string
+		backupBucket *extensionsv1alpha1.BackupBucket
+		want         bool
+	}{
+		{"no condition", &extensionsv1alpha1.BackupBucket{}, false},
+		{"condition false", backupBucketWithCondition(gardencorev1beta1.ConditionFalse), false},
+		{"condition unknown", backupBucketWithCondition(gardencorev1beta1.ConditionUnknown), false},
+		{"condition true", backupBucketWithCondition(gardencorev1beta1.ConditionTrue), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.isKeyRotationAcknowledged(tt.backupBucket); got != tt.want {
+				t.Errorf("isKeyRotationAcknowledged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsKeyRotationPending(t *testing.T) {
+	a := &actuator{}
+
+	tests := []struct {
+		name         string
+		backupBucket *extensionsv1alpha1.BackupBucket
+		want         bool
+	}{
+		{"no condition", &extensionsv1alpha1.BackupBucket{}, false},
+		{"condition false", backupBucketWithCondition(gardencorev1beta1.ConditionFalse), true},
+		{"condition true", backupBucketWithCondition(gardencorev1beta1.ConditionTrue), false},
+		{"condition unknown", backupBucketWithCondition(gardencorev1beta1.ConditionUnknown), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.isKeyRotationPending(tt.backupBucket); got != tt.want {
+				t.Errorf("isKeyRotationPending() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldBeRotated(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		secret  corev1.Secret
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "fresh secret, never rotated",
+			secret: corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now)},
+			},
+			want: false,
+		},
+		{
+			name: "old secret, never rotated",
+			secret: corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.AddDate(0, 0, -15))},
+			},
+			want: true,
+		},
+		{
+			name: "old secret, rotated recently",
+			secret: corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.NewTime(now.AddDate(0, 0, -30)),
+					Annotations: map[string]string{
+						rotationTimestampAnnotation: now.UTC().Format(time.RFC3339),
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "old secret, rotated long ago",
+			secret: corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.NewTime(now.AddDate(0, 0, -30)),
+					Annotations: map[string]string{
+						rotationTimestampAnnotation: now.AddDate(0, 0, -15).UTC().Format(time.RFC3339),
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "malformed rotation timestamp annotation",
+			secret: corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						rotationTimestampAnnotation: "not-a-timestamp",
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := shouldBeRotated(tt.secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("shouldBeRotated() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("shouldBeRotated() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,9 +18,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/gardener/gardener-extension-provider-azure/pkg/azure"
+	azureclient "github.com/gardener/gardener-extension-provider-azure/pkg/azure/client"
 )
 
-func (a *actuator) createBackupBucketGeneratedSecret(ctx context.Context, backupBucket *extensionsv1alpha1.BackupBucket, storageAccountName, storageKey, storageDomain string) error {
+func (a *actuator) createBackupBucketGeneratedSecret(ctx context.Context, backupBucket *extensionsv1alpha1.BackupBucket, storageAccountName, storageKey, storageDomain string, identity *azureclient.WorkloadIdentity) error {
 	timestampHash := utils.ComputeSHA256Hex([]byte(time.Now().Format(time.RFC3339)))[:4]
 	var generatedSecret = &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -30,6 +31,17 @@ func (a *actuator) createBackupBucketGeneratedSecret(ctx context.Context, backup
 	}
 
 	if _, err := controllerutil.CreateOrUpdate(ctx, a.client, generatedSecret, func() error {
+		if identity != nil {
+			// The sidecar authenticates to Blob storage itself via Workload Identity / managed identity, so no
+			// storage-account key is handed out.
+			generatedSecret.Data = map[string][]byte{
+				azure.StorageAccount: []byte(storageAccountName),
+				azure.StorageDomain:  []byte(storageDomain),
+				azure.TenantID:       []byte(identity.TenantID),
+				azure.ClientID:       []byte(identity.ClientID),
+			}
+			return nil
+		}
 		generatedSecret.Data = map[string][]byte{
 			azure.StorageAccount: []byte(storageAccountName),
 			azure.StorageKey:     []byte(storageKey),
@@ -76,6 +88,21 @@ func (a *actuator) getBackupBucketGeneratedSecret(ctx context.Context, backupBuc
 	return secret, nil
 }
 
+// storageAccountKeyRotationInterval is the minimum age of the active storage account key before
+// EnsureStorageAccountKeyRotation considers it due for rotation.
+const storageAccountKeyRotationInterval = 14 * 24 * time.Hour
+
+// shouldBeRotated reports whether the storage account key backing secret is old enough to warrant rotation. It
+// prefers rotationTimestampAnnotation, which records when the key currently in use was actually (re)generated, and
+// only falls back to the secret's creation timestamp for secrets that have never gone through a rotation.
 func shouldBeRotated(secret corev1.Secret) (bool, error) {
-	return secret.CreationTimestamp.Time.Before(time.Now().AddDate(0, 0, -14)), nil
+	lastRotated := secret.CreationTimestamp.Time
+	if raw, ok := secret.Annotations[rotationTimestampAnnotation]; ok {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return false, fmt.Errorf("failed to parse %s annotation: %w", rotationTimestampAnnotation, err)
+		}
+		lastRotated = parsed
+	}
+	return lastRotated.Before(time.Now().Add(-storageAccountKeyRotationInterval)), nil
 }